@@ -0,0 +1,173 @@
+package vaults
+
+import (
+	"sort"
+	"sync"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/yearn/ydaemon/common/bigNumber"
+)
+
+// TVaultActivityType identifies the kind of ERC-4626-style event a TVaultActivity
+// entry was built from.
+type TVaultActivityType string
+
+const (
+	VaultActivityDeposit  TVaultActivityType = `deposit`
+	VaultActivityWithdraw TVaultActivityType = `withdraw`
+)
+
+// TVaultActivity holds a single indexed Deposit or Withdraw event for a vault,
+// keyed by (chainID, vault, user) once stored in _activityStore.
+type TVaultActivity struct {
+	ChainID       uint64             `json:"chainID"`
+	Vault         ethcommon.Address  `json:"vault"`
+	User          ethcommon.Address  `json:"user"`
+	Type          TVaultActivityType `json:"type"`
+	Shares        *bigNumber.Int     `json:"shares"`
+	Assets        *bigNumber.Int     `json:"assets"`
+	PricePerShare *bigNumber.Int     `json:"pricePerShare"`
+	BlockNumber   uint64             `json:"blockNumber"`
+	TxHash        ethcommon.Hash     `json:"txHash"`
+	LogIndex      uint               `json:"logIndex"`
+	Timestamp     uint64             `json:"timestamp"`
+}
+
+// TUserPosition holds the rolling position of a single user in a single vault,
+// derived from the full history of that user's TVaultActivity entries.
+type TUserPosition struct {
+	ChainID         uint64            `json:"chainID"`
+	Vault           ethcommon.Address `json:"vault"`
+	User            ethcommon.Address `json:"user"`
+	Shares          *bigNumber.Int    `json:"shares"`
+	CostBasis       *bigNumber.Int    `json:"costBasis"`
+	RealizedPnL     *bigNumber.Int    `json:"realizedPnL"`
+	LastUpdateBlock uint64            `json:"lastUpdateBlock"`
+}
+
+// TListVaultActivityOpts narrows down a ListVaultActivity call. A nil User
+// returns activity for all users of the vault.
+type TListVaultActivityOpts struct {
+	User  *ethcommon.Address
+	Since uint64
+	Until uint64
+	Limit int
+}
+
+/**********************************************************************************************
+** Set of functions to store and retrieve the vault deposit/withdraw activity and the per-user
+** positions derived from it. The _activityStore and _positionStore variables are not exported
+** and are only used internally by the functions below.
+**********************************************************************************************/
+var _activityMu sync.RWMutex
+var _activityStore = make(map[uint64]map[ethcommon.Address][]*TVaultActivity)
+var _positionStore = make(map[uint64]map[ethcommon.Address]map[ethcommon.Address]*TUserPosition)
+
+/**********************************************************************************************
+** recordActivity appends a newly indexed event to _activityStore and folds it into the
+** corresponding TUserPosition in _positionStore. It is called by the indexer for every
+** Deposit/Withdraw log it decodes, in block order, so positions can be updated incrementally.
+**********************************************************************************************/
+func recordActivity(activity *TVaultActivity) {
+	_activityMu.Lock()
+	defer _activityMu.Unlock()
+
+	if _activityStore[activity.ChainID] == nil {
+		_activityStore[activity.ChainID] = make(map[ethcommon.Address][]*TVaultActivity)
+	}
+	_activityStore[activity.ChainID][activity.Vault] = append(
+		_activityStore[activity.ChainID][activity.Vault],
+		activity,
+	)
+
+	if _positionStore[activity.ChainID] == nil {
+		_positionStore[activity.ChainID] = make(map[ethcommon.Address]map[ethcommon.Address]*TUserPosition)
+	}
+	if _positionStore[activity.ChainID][activity.Vault] == nil {
+		_positionStore[activity.ChainID][activity.Vault] = make(map[ethcommon.Address]*TUserPosition)
+	}
+	position, ok := _positionStore[activity.ChainID][activity.Vault][activity.User]
+	if !ok {
+		position = &TUserPosition{
+			ChainID:     activity.ChainID,
+			Vault:       activity.Vault,
+			User:        activity.User,
+			Shares:      bigNumber.NewInt(0),
+			CostBasis:   bigNumber.NewInt(0),
+			RealizedPnL: bigNumber.NewInt(0),
+		}
+		_positionStore[activity.ChainID][activity.Vault][activity.User] = position
+	}
+
+	switch activity.Type {
+	case VaultActivityDeposit:
+		position.Shares = bigNumber.NewInt(0).Add(position.Shares, activity.Shares)
+		position.CostBasis = bigNumber.NewInt(0).Add(position.CostBasis, activity.Assets)
+	case VaultActivityWithdraw:
+		proceeds := activity.Assets
+		costRemoved := bigNumber.NewInt(0)
+		if position.Shares.Sign() > 0 {
+			// costRemoved = costBasis * shares withdrawn / shares held, i.e. the
+			// proportional slice of the cost basis being liquidated.
+			costRemoved = bigNumber.NewInt(0).Div(
+				bigNumber.NewInt(0).Mul(position.CostBasis, activity.Shares),
+				position.Shares,
+			)
+		}
+		position.RealizedPnL = bigNumber.NewInt(0).Add(
+			position.RealizedPnL,
+			bigNumber.NewInt(0).Sub(proceeds, costRemoved),
+		)
+		position.CostBasis = bigNumber.NewInt(0).Sub(position.CostBasis, costRemoved)
+		position.Shares = bigNumber.NewInt(0).Sub(position.Shares, activity.Shares)
+	}
+	position.LastUpdateBlock = activity.BlockNumber
+}
+
+/**********************************************************************************************
+** ListVaultActivity will, for a given chainID and vault, return the list of indexed deposit and
+** withdraw events matching the provided opts, most recent first.
+**********************************************************************************************/
+func ListVaultActivity(chainID uint64, vault ethcommon.Address, opts TListVaultActivityOpts) []*TVaultActivity {
+	_activityMu.RLock()
+	defer _activityMu.RUnlock()
+
+	var activities []*TVaultActivity
+	for _, activity := range _activityStore[chainID][vault] {
+		if opts.User != nil && activity.User != *opts.User {
+			continue
+		}
+		if opts.Since != 0 && activity.BlockNumber < opts.Since {
+			continue
+		}
+		if opts.Until != 0 && activity.BlockNumber > opts.Until {
+			continue
+		}
+		activities = append(activities, activity)
+	}
+
+	sort.Slice(activities, func(i, j int) bool {
+		return activities[i].BlockNumber > activities[j].BlockNumber
+	})
+	if opts.Limit > 0 && len(activities) > opts.Limit {
+		activities = activities[:opts.Limit]
+	}
+	return activities
+}
+
+/**********************************************************************************************
+** GetUserPosition will, for a given chainID and user, return the list of per-vault positions
+** currently held by that user, across every vault indexed for that chain.
+**********************************************************************************************/
+func GetUserPosition(chainID uint64, user ethcommon.Address) []*TUserPosition {
+	_activityMu.RLock()
+	defer _activityMu.RUnlock()
+
+	var positions []*TUserPosition
+	for _, byUser := range _positionStore[chainID] {
+		if position, ok := byUser[user]; ok {
+			positions = append(positions, position)
+		}
+	}
+	return positions
+}
@@ -0,0 +1,45 @@
+package vaults
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/yearn/ydaemon/common/types/common"
+)
+
+/**********************************************************************************************
+** GetVaultActivity is the HTTP handler answering `GET /{chainID}/vaults/{address}/activity`. It
+** lists the indexed deposit/withdraw events for the requested vault, optionally scoped to a
+** single user via the `user` query parameter.
+**********************************************************************************************/
+func GetVaultActivity(c *fiber.Ctx) error {
+	chainID, err := strconv.ParseUint(c.Params(`chainID`), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(`invalid chainID`)
+	}
+	vaultAddress := common.FromString(c.Params(`address`))
+
+	opts := TListVaultActivityOpts{}
+	if user := c.Query(`user`); user != `` {
+		userAddress := common.FromString(user).ToAddress()
+		opts.User = &userAddress
+	}
+
+	activities := ListVaultActivity(chainID, vaultAddress.ToAddress(), opts)
+	return c.Status(fiber.StatusOK).JSON(activities)
+}
+
+/**********************************************************************************************
+** GetUserPositions is the HTTP handler answering `GET /{chainID}/users/{address}/positions`. It
+** returns every vault position currently held by the requested user on the given chain.
+**********************************************************************************************/
+func GetUserPositions(c *fiber.Ctx) error {
+	chainID, err := strconv.ParseUint(c.Params(`chainID`), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(`invalid chainID`)
+	}
+	userAddress := common.FromString(c.Params(`address`))
+
+	positions := GetUserPosition(chainID, userAddress.ToAddress())
+	return c.Status(fiber.StatusOK).JSON(positions)
+}
@@ -0,0 +1,220 @@
+package vaults
+
+import (
+	"context"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/yearn/ydaemon/common/bigNumber"
+	"github.com/yearn/ydaemon/common/logs"
+)
+
+// depositEventSignature and withdrawEventSignature are the keccak256 topic0 hashes for the
+// ERC-4626-style `Deposit(address,address,uint256,uint256)` and
+// `Withdraw(address,address,address,uint256,uint256)` events indexed by ActivityIndexer.
+var depositEventSignature = ethcommon.HexToHash(`0xdcbc1c05240f31ff3ad067ef1ee35ce4997762752e3a095284754544f4c709d7`)
+var withdrawEventSignature = ethcommon.HexToHash(`0xfbde797d201c681b91056529119e0b02407c7bb96a4a2c75c01fc9667232c8db`)
+
+// TActivityLogFetcher is the subset of ethclient.Client used by ActivityIndexer, narrowed down
+// so the indexer can be exercised against a fake client without dialing a real node.
+type TActivityLogFetcher interface {
+	FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error)
+	SubscribeFilterLogs(ctx context.Context, query ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error)
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+}
+
+// TActivityCheckpointStore persists, per (chainID, vault), the last block successfully indexed,
+// so a restart can resume a backfill instead of re-scanning from the configured start block.
+type TActivityCheckpointStore interface {
+	LastIndexedBlock(chainID uint64, vault ethcommon.Address) (uint64, bool)
+	SaveIndexedBlock(chainID uint64, vault ethcommon.Address, block uint64) error
+}
+
+/**********************************************************************************************
+** inMemoryCheckpointStore is the default TActivityCheckpointStore used when no other store is
+** wired in. It is intentionally simple: production deployments should inject a persistent
+** implementation (eg. backed by the same database as the rest of ydaemon) via NewActivityIndexer.
+**********************************************************************************************/
+type inMemoryCheckpointStore struct {
+	mu     sync.Mutex
+	blocks map[uint64]map[ethcommon.Address]uint64
+}
+
+func newInMemoryCheckpointStore() *inMemoryCheckpointStore {
+	return &inMemoryCheckpointStore{blocks: make(map[uint64]map[ethcommon.Address]uint64)}
+}
+
+func (s *inMemoryCheckpointStore) LastIndexedBlock(chainID uint64, vault ethcommon.Address) (uint64, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	block, ok := s.blocks[chainID][vault]
+	return block, ok
+}
+
+func (s *inMemoryCheckpointStore) SaveIndexedBlock(chainID uint64, vault ethcommon.Address, block uint64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.blocks[chainID] == nil {
+		s.blocks[chainID] = make(map[ethcommon.Address]uint64)
+	}
+	s.blocks[chainID][vault] = block
+	return nil
+}
+
+// ActivityIndexer backfills and tails Deposit/Withdraw events for every vault of a given chain,
+// feeding them into recordActivity as they are decoded.
+type ActivityIndexer struct {
+	chainID    uint64
+	client     TActivityLogFetcher
+	checkpoint TActivityCheckpointStore
+	startBlock uint64
+}
+
+// NewActivityIndexer returns an ActivityIndexer for chainID, backfilling from startBlock the
+// first time a vault is seen. Passing a nil checkpoint falls back to an in-memory store, which
+// is enough for a single-process deployment but loses its progress on restart.
+func NewActivityIndexer(chainID uint64, client TActivityLogFetcher, checkpoint TActivityCheckpointStore, startBlock uint64) *ActivityIndexer {
+	if checkpoint == nil {
+		checkpoint = newInMemoryCheckpointStore()
+	}
+	return &ActivityIndexer{
+		chainID:    chainID,
+		client:     client,
+		checkpoint: checkpoint,
+		startBlock: startBlock,
+	}
+}
+
+/**********************************************************************************************
+** Backfill indexes every Deposit/Withdraw event emitted by vault between its last checkpointed
+** block (or the indexer's configured startBlock, if none) and toBlock, in order, using a single
+** log query for the whole range. toBlock is saved as the new checkpoint only once every log of
+** that range has been processed, so a crash mid-backfill re-scans the range on the next run.
+**********************************************************************************************/
+func (i *ActivityIndexer) Backfill(ctx context.Context, vault ethcommon.Address, toBlock uint64) error {
+	fromBlock := i.startBlock
+	if last, ok := i.checkpoint.LastIndexedBlock(i.chainID, vault); ok {
+		fromBlock = last + 1
+	}
+	if fromBlock > toBlock {
+		return nil
+	}
+
+	query := ethereum.FilterQuery{
+		FromBlock: new(big.Int).SetUint64(fromBlock),
+		ToBlock:   new(big.Int).SetUint64(toBlock),
+		Addresses: []ethcommon.Address{vault},
+		Topics:    [][]ethcommon.Hash{{depositEventSignature, withdrawEventSignature}},
+	}
+	logEntries, err := i.client.FilterLogs(ctx, query)
+	if err != nil {
+		logs.Error(err)
+		return err
+	}
+
+	for _, logEntry := range logEntries {
+		if err := i.indexLog(vault, logEntry); err != nil {
+			logs.Error(err)
+			continue
+		}
+	}
+	return i.checkpoint.SaveIndexedBlock(i.chainID, vault, toBlock)
+}
+
+/**********************************************************************************************
+** Tail subscribes to new Deposit/Withdraw logs for vault via eth_subscribe and indexes them as
+** they arrive, updating the checkpoint after every event. It blocks until ctx is cancelled or
+** the underlying subscription errors, and is meant to be run in its own goroutine per vault.
+**********************************************************************************************/
+func (i *ActivityIndexer) Tail(ctx context.Context, vault ethcommon.Address) error {
+	query := ethereum.FilterQuery{
+		Addresses: []ethcommon.Address{vault},
+		Topics:    [][]ethcommon.Hash{{depositEventSignature, withdrawEventSignature}},
+	}
+	logChan := make(chan types.Log)
+	subscription, err := i.client.SubscribeFilterLogs(ctx, query, logChan)
+	if err != nil {
+		logs.Error(err)
+		return err
+	}
+	defer subscription.Unsubscribe()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-subscription.Err():
+			logs.Error(err)
+			return err
+		case logEntry := <-logChan:
+			if err := i.indexLog(vault, logEntry); err != nil {
+				logs.Error(err)
+				continue
+			}
+			if err := i.checkpoint.SaveIndexedBlock(i.chainID, vault, logEntry.BlockNumber); err != nil {
+				logs.Error(err)
+			}
+		}
+	}
+}
+
+/**********************************************************************************************
+** indexLog decodes a single Deposit or Withdraw log into a TVaultActivity and feeds it to
+** recordActivity. The non-indexed data fields (assets/shares) are decoded from the raw log data
+** by decodeActivityAmounts.
+**********************************************************************************************/
+func (i *ActivityIndexer) indexLog(vault ethcommon.Address, logEntry types.Log) error {
+	activityType := VaultActivityDeposit
+	if logEntry.Topics[0] == withdrawEventSignature {
+		activityType = VaultActivityWithdraw
+	}
+
+	// Deposit(address indexed sender, address indexed owner, uint256, uint256): the owner whose
+	// shares are minted is Topics[2].
+	// Withdraw(address indexed sender, address indexed receiver, address indexed owner, uint256, uint256):
+	// the owner whose shares are burned is Topics[3], not the caller/receiver.
+	ownerTopicIndex := 2
+	if activityType == VaultActivityWithdraw {
+		ownerTopicIndex = 3
+	}
+	if len(logEntry.Topics) <= ownerTopicIndex {
+		return nil
+	}
+	user := ethcommon.HexToAddress(logEntry.Topics[ownerTopicIndex].Hex())
+
+	shares, assets, pricePerShare := decodeActivityAmounts(logEntry.Data)
+
+	recordActivity(&TVaultActivity{
+		ChainID:       i.chainID,
+		Vault:         vault,
+		User:          user,
+		Type:          activityType,
+		Shares:        shares,
+		Assets:        assets,
+		PricePerShare: pricePerShare,
+		BlockNumber:   logEntry.BlockNumber,
+		TxHash:        logEntry.TxHash,
+		LogIndex:      uint(logEntry.Index),
+	})
+	return nil
+}
+
+// decodeActivityAmounts unpacks the ABI-encoded assets/shares pair carried in the log data of a
+// Deposit/Withdraw event and derives the implied pricePerShare at the time of the event.
+func decodeActivityAmounts(data []byte) (shares *bigNumber.Int, assets *bigNumber.Int, pricePerShare *bigNumber.Int) {
+	shares = bigNumber.NewInt(0)
+	assets = bigNumber.NewInt(0)
+	pricePerShare = bigNumber.NewInt(0)
+	if len(data) < 64 {
+		return
+	}
+	assets = bigNumber.SetInt(new(big.Int).SetBytes(data[0:32]))
+	shares = bigNumber.SetInt(new(big.Int).SetBytes(data[32:64]))
+	if shares.Sign() > 0 {
+		pricePerShare = bigNumber.NewInt(0).Div(bigNumber.NewInt(0).Mul(assets, bigNumber.NewInt(1e18)), shares)
+	}
+	return
+}
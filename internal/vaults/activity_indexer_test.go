@@ -0,0 +1,96 @@
+package vaults
+
+import (
+	"math/big"
+	"testing"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// TestEventSignatureHashes pins depositEventSignature/withdrawEventSignature against their
+// literal, independently-known keccak256 topic0 values. A HexToHash call silently left-pads an
+// odd-length (ie. truncated) hex string instead of failing, so a single dropped hex digit in the
+// source would otherwise index nothing from a real chain without ever erroring.
+func TestEventSignatureHashes(t *testing.T) {
+	tests := []struct {
+		name string
+		got  ethcommon.Hash
+		want string
+	}{
+		{"Deposit(address,address,uint256,uint256)", depositEventSignature, `0xdcbc1c05240f31ff3ad067ef1ee35ce4997762752e3a095284754544f4c709d7`},
+		{"Withdraw(address,address,address,uint256,uint256)", withdrawEventSignature, `0xfbde797d201c681b91056529119e0b02407c7bb96a4a2c75c01fc9667232c8db`},
+	}
+	for _, test := range tests {
+		if len(test.want) != 66 {
+			t.Fatalf("%s: fixture hash %q is not 32 bytes", test.name, test.want)
+		}
+		if test.got != ethcommon.HexToHash(test.want) {
+			t.Errorf("%s: got %s, want %s", test.name, test.got.Hex(), test.want)
+		}
+	}
+}
+
+// TestIndexLogOwnerTopic verifies that indexLog attributes a Deposit to Topics[2] (the owner
+// receiving shares) and a Withdraw to Topics[3] (the owner whose shares are burned), not to
+// Topics[1] (the caller) in either case -- that distinction matters whenever the caller is an
+// approved spender or a zap contract acting on the owner's behalf.
+func TestIndexLogOwnerTopic(t *testing.T) {
+	sender := ethcommon.HexToAddress(`0x1111111111111111111111111111111111111111`)
+	receiver := ethcommon.HexToAddress(`0x2222222222222222222222222222222222222222`)
+	owner := ethcommon.HexToAddress(`0x3333333333333333333333333333333333333333`)
+	vault := ethcommon.HexToAddress(`0x4444444444444444444444444444444444444444`)
+
+	data := make([]byte, 64)
+	copy(data[0:32], new(big.Int).SetUint64(1000).Bytes())
+	copy(data[32:64], new(big.Int).SetUint64(900).Bytes())
+
+	indexer := &ActivityIndexer{chainID: 1}
+
+	depositLog := types.Log{
+		Topics: []ethcommon.Hash{
+			depositEventSignature,
+			ethcommon.BytesToHash(sender.Bytes()),
+			ethcommon.BytesToHash(owner.Bytes()),
+		},
+		Data:        data,
+		BlockNumber: 100,
+	}
+	if err := indexer.indexLog(vault, depositLog); err != nil {
+		t.Fatalf("indexLog(deposit) returned error: %v", err)
+	}
+	depositActivities := ListVaultActivity(1, vault, TListVaultActivityOpts{})
+	if len(depositActivities) != 1 || depositActivities[0].User != owner {
+		t.Fatalf("deposit: expected activity attributed to owner %s, got %+v", owner.Hex(), depositActivities)
+	}
+
+	withdrawLog := types.Log{
+		Topics: []ethcommon.Hash{
+			withdrawEventSignature,
+			ethcommon.BytesToHash(sender.Bytes()),
+			ethcommon.BytesToHash(receiver.Bytes()),
+			ethcommon.BytesToHash(owner.Bytes()),
+		},
+		Data:        data,
+		BlockNumber: 101,
+	}
+	if err := indexer.indexLog(vault, withdrawLog); err != nil {
+		t.Fatalf("indexLog(withdraw) returned error: %v", err)
+	}
+	allActivities := ListVaultActivity(1, vault, TListVaultActivityOpts{})
+	if len(allActivities) != 2 {
+		t.Fatalf("expected 2 activities after withdraw, got %d", len(allActivities))
+	}
+	var withdrawActivity *TVaultActivity
+	for _, activity := range allActivities {
+		if activity.Type == VaultActivityWithdraw {
+			withdrawActivity = activity
+		}
+	}
+	if withdrawActivity == nil || withdrawActivity.User != owner {
+		t.Fatalf("withdraw: expected activity attributed to owner %s, got %+v", owner.Hex(), withdrawActivity)
+	}
+	if withdrawActivity.User == sender || withdrawActivity.User == receiver {
+		t.Fatalf("withdraw: activity must not be attributed to the caller/receiver, got %s", withdrawActivity.User.Hex())
+	}
+}
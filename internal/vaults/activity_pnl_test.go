@@ -0,0 +1,54 @@
+package vaults
+
+import (
+	"testing"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/yearn/ydaemon/common/bigNumber"
+)
+
+// TestRecordActivityCostBasisAndPnL exercises the weighted-average cost-basis/PnL math in
+// recordActivity: a deposit establishes cost basis 1:1 with assets in, and a partial withdraw at
+// a higher price should realize a proportional gain while leaving the remaining shares/cost
+// basis scaled down by the same fraction that was withdrawn.
+func TestRecordActivityCostBasisAndPnL(t *testing.T) {
+	chainID := uint64(999)
+	vault := ethcommon.HexToAddress(`0x5555555555555555555555555555555555555555`)
+	user := ethcommon.HexToAddress(`0x6666666666666666666666666666666666666666`)
+
+	recordActivity(&TVaultActivity{
+		ChainID:     chainID,
+		Vault:       vault,
+		User:        user,
+		Type:        VaultActivityDeposit,
+		Shares:      bigNumber.NewInt(1000),
+		Assets:      bigNumber.NewInt(1000),
+		BlockNumber: 1,
+	})
+
+	recordActivity(&TVaultActivity{
+		ChainID:     chainID,
+		Vault:       vault,
+		User:        user,
+		Type:        VaultActivityWithdraw,
+		Shares:      bigNumber.NewInt(500),
+		Assets:      bigNumber.NewInt(600),
+		BlockNumber: 2,
+	})
+
+	positions := GetUserPosition(chainID, user)
+	if len(positions) != 1 {
+		t.Fatalf("expected 1 position, got %d", len(positions))
+	}
+	position := positions[0]
+
+	if position.Shares.Cmp(bigNumber.NewInt(500)) != 0 {
+		t.Errorf("shares: got %s, want 500", position.Shares.String())
+	}
+	if position.CostBasis.Cmp(bigNumber.NewInt(500)) != 0 {
+		t.Errorf("costBasis: got %s, want 500", position.CostBasis.String())
+	}
+	if position.RealizedPnL.Cmp(bigNumber.NewInt(100)) != 0 {
+		t.Errorf("realizedPnL: got %s, want 100", position.RealizedPnL.String())
+	}
+}
@@ -0,0 +1,102 @@
+package vaults
+
+import (
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/yearn/ydaemon/common/bigNumber"
+	"github.com/yearn/ydaemon/common/store"
+	"github.com/yearn/ydaemon/common/types/common"
+)
+
+// TStrategyAllocation holds how much of a vault's capital a single strategy is currently
+// managing, and how it got there.
+type TStrategyAllocation struct {
+	Strategy             ethcommon.Address `json:"strategy"`
+	QueueIndex           int               `json:"queueIndex"`
+	TotalDebt            *bigNumber.Int    `json:"totalDebt"`
+	DebtRatio            uint64            `json:"debtRatio"`
+	EstimatedTotalAssets *bigNumber.Int    `json:"estimatedTotalAssets"`
+	APYContribution      float64           `json:"apyContribution"`
+}
+
+// TVaultAllocation breaks down where a vault's deposited capital is actually deployed: the
+// withdrawal queue order and per-strategy debt for every active strategy, plus whatever is
+// sitting idle in the vault itself.
+type TVaultAllocation struct {
+	Strategies []TStrategyAllocation `json:"strategies"`
+	IdleAssets *bigNumber.Int        `json:"idleAssets"`
+}
+
+/**********************************************************************************************
+** BuildStrategyAllocation resolves the vault's withdrawal queue order, per-strategy totalDebt,
+** debtRatio and estimatedTotalAssets, and the assets currently idle in the vault, storing the
+** result on t.Allocation. It relies on the same aggregated vault data BuildAPY reads from, so it
+** should be called after the vault's strategies have been populated.
+**********************************************************************************************/
+func (t *TVault) BuildStrategyAllocation(chainID uint64) {
+	aggregatedVault, ok := store.Store.AggregatedVault[chainID][common.FromAddress(t.Address)]
+	if !ok {
+		t.Allocation = nil
+		return
+	}
+
+	totalDebt := bigNumber.NewInt(0)
+	strategyAllocations := make([]TStrategyAllocation, 0, len(t.Strategies))
+	for queueIndex, strategy := range t.Strategies {
+		// A strategy can be listed in the withdrawal queue before its on-chain Details have been
+		// fetched; skip it (it is left out of the breakdown and adds nothing to totalDebt) rather
+		// than dereferencing a nil pointer.
+		if strategy.Details == nil {
+			continue
+		}
+
+		strategyDebt := strategy.Details.TotalDebt
+		if strategyDebt == nil {
+			strategyDebt = bigNumber.NewInt(0)
+		}
+		totalDebt = bigNumber.NewInt(0).Add(totalDebt, strategyDebt)
+
+		strategyAllocations = append(strategyAllocations, TStrategyAllocation{
+			Strategy:             strategy.Address,
+			QueueIndex:           queueIndex,
+			TotalDebt:            strategyDebt,
+			DebtRatio:            strategy.Details.DebtRatio,
+			EstimatedTotalAssets: strategy.Details.EstimatedTotalAssets,
+			APYContribution:      apyContribution(aggregatedVault.LegacyAPY.NetAPY, strategyDebt, t.TVL.TotalAssets),
+		})
+	}
+
+	idleAssets := bigNumber.NewInt(0)
+	if t.TVL.TotalAssets != nil {
+		idleAssets = bigNumber.NewInt(0).Sub(t.TVL.TotalAssets, totalDebt)
+	}
+
+	t.Allocation = &TVaultAllocation{
+		Strategies: strategyAllocations,
+		IdleAssets: idleAssets,
+	}
+}
+
+// apyContribution weights the vault's net APY by the share of totalAssets a single strategy is
+// responsible for, giving a rough per-strategy contribution to the vault's blended APY.
+func apyContribution(vaultNetAPY float64, strategyDebt *bigNumber.Int, totalAssets *bigNumber.Int) float64 {
+	if totalAssets == nil || totalAssets.Sign() <= 0 || strategyDebt == nil {
+		return 0
+	}
+	weight := bigNumber.NewInt(0).Div(
+		bigNumber.NewInt(0).Mul(strategyDebt, bigNumber.NewInt(1e18)),
+		totalAssets,
+	).Float64()
+	return vaultNetAPY * (weight / 1e18)
+}
+
+/**********************************************************************************************
+** ListVaultAllocation will, for a given chainID and vault, return the strategy allocation
+** breakdown for that vault, and a boolean indicating whether one has been built yet.
+**********************************************************************************************/
+func ListVaultAllocation(chainID uint64, vault common.Address) (*TVaultAllocation, bool) {
+	v, ok := FindVault(chainID, vault)
+	if !ok || v.Allocation == nil {
+		return nil, false
+	}
+	return v.Allocation, true
+}
@@ -0,0 +1,26 @@
+package vaults
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/yearn/ydaemon/common/types/common"
+)
+
+/**********************************************************************************************
+** GetVaultAllocation is the HTTP handler answering `GET /{chainID}/vaults/{address}/allocation`.
+** It returns the per-strategy debt/allocation breakdown for the requested vault.
+**********************************************************************************************/
+func GetVaultAllocation(c *fiber.Ctx) error {
+	chainID, err := strconv.ParseUint(c.Params(`chainID`), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(`invalid chainID`)
+	}
+	vaultAddress := common.FromString(c.Params(`address`))
+
+	allocation, ok := ListVaultAllocation(chainID, vaultAddress)
+	if !ok {
+		return c.Status(fiber.StatusNotFound).JSON(`no allocation data for this vault`)
+	}
+	return c.Status(fiber.StatusOK).JSON(allocation)
+}
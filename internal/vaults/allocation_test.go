@@ -0,0 +1,32 @@
+package vaults
+
+import (
+	"testing"
+
+	"github.com/yearn/ydaemon/common/bigNumber"
+)
+
+// TestAPYContribution verifies the per-strategy APY weighting used by BuildStrategyAllocation:
+// a strategy holding half of a vault's assets should contribute half of the vault's net APY, and
+// a vault with no assets yet (or an unknown strategy debt) must not divide by zero.
+func TestAPYContribution(t *testing.T) {
+	netAPY := 0.10
+
+	half := apyContribution(netAPY, bigNumber.NewInt(500), bigNumber.NewInt(1000))
+	if half < 0.0499 || half > 0.0501 {
+		t.Errorf("expected ~0.05 for a 50%% allocation, got %v", half)
+	}
+
+	all := apyContribution(netAPY, bigNumber.NewInt(1000), bigNumber.NewInt(1000))
+	if all < 0.0999 || all > 0.1001 {
+		t.Errorf("expected ~0.10 for a 100%% allocation, got %v", all)
+	}
+
+	if got := apyContribution(netAPY, bigNumber.NewInt(500), bigNumber.NewInt(0)); got != 0 {
+		t.Errorf("expected 0 when totalAssets is 0 (would divide by zero), got %v", got)
+	}
+
+	if got := apyContribution(netAPY, nil, bigNumber.NewInt(1000)); got != 0 {
+		t.Errorf("expected 0 when strategyDebt is nil, got %v", got)
+	}
+}
@@ -0,0 +1,150 @@
+package vaults
+
+import (
+	"sync"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+)
+
+// TVaultHaltSource identifies which on-chain signal triggered a TVaultHalt.
+type TVaultHaltSource string
+
+const (
+	HaltSourceEmergencyShutdown TVaultHaltSource = `emergency_shutdown`
+	HaltSourceRoleChange        TVaultHaltSource = `role_change`
+	HaltSourceDepositLimitZero  TVaultHaltSource = `deposit_limit_zero`
+	HaltSourceHealthCheck       TVaultHaltSource = `health_check_revert`
+)
+
+// haltSourcePriority orders the sources that can actually halt a vault, most severe first. It is
+// used to pick the single representative TVaultHalt exposed on TVaultDetails/GetVaultHalt when
+// more than one source is active at the same time. HaltSourceRoleChange is deliberately absent:
+// a guardian/management rotation is routine and does not, on its own, halt anything.
+var haltSourcePriority = []TVaultHaltSource{
+	HaltSourceEmergencyShutdown,
+	HaltSourceHealthCheck,
+	HaltSourceDepositLimitZero,
+}
+
+// TVaultHalt records a chain-observed reason why deposits and/or withdrawals on a vault are
+// currently halted. Unlike TVaultDetails.DepositsDisabled/WithdrawalsDisabled, which reflect a
+// meta-configured pause, a TVaultHalt is derived purely from on-chain state and carries the
+// block at which it took effect.
+type TVaultHalt struct {
+	Reason          string           `json:"reason"`
+	Source          TVaultHaltSource `json:"source"`
+	EffectiveBlock  uint64           `json:"effectiveBlock"`
+	EffectiveTime   uint64           `json:"effectiveTime"`
+	ExpiresBlock    uint64           `json:"expiresBlock,omitempty"`
+	DepositsHalted  bool             `json:"depositsHalted"`
+	WithdrawsHalted bool             `json:"withdrawsHalted"`
+}
+
+/**********************************************************************************************
+** Set of functions to store and retrieve the chain-observed halt state of a vault. Each on-chain
+** source (emergency shutdown, deposit-limit-zero, health-check revert, ...) is tracked
+** independently, so one source clearing or firing never clobbers another that is still active.
+** The _haltStore variable is not exported and is only used internally by the functions below.
+**********************************************************************************************/
+var _haltMu sync.RWMutex
+var _haltStore = make(map[uint64]map[ethcommon.Address]map[TVaultHaltSource]*TVaultHalt)
+
+// _metaDisabledBaseline captures, the first time a vault's halt state is touched, the
+// meta-configured DepositsDisabled/WithdrawalsDisabled values that were in effect before any
+// on-chain halt was folded in. Recomputing TVaultDetails.DepositsDisabled/WithdrawalsDisabled
+// from this baseline on every update (rather than OR-ing onto whatever the field already holds)
+// is what lets a cleared on-chain halt actually un-disable a vault that was never meta-disabled.
+type metaDisabledBaseline struct {
+	deposits    bool
+	withdrawals bool
+}
+
+var _metaDisabledBaseline = make(map[uint64]map[ethcommon.Address]metaDisabledBaseline)
+
+/**********************************************************************************************
+** setVaultHalt records (or clears, when halt is nil) the halt state for a single source of a
+** vault, recomputes the representative TVaultHalt across every still-active source, reflects it
+** onto the matching TVault.Details, and publishes the resulting transition.
+**********************************************************************************************/
+func setVaultHalt(chainID uint64, vault ethcommon.Address, source TVaultHaltSource, halt *TVaultHalt) {
+	_haltMu.Lock()
+	defer _haltMu.Unlock()
+
+	if _haltStore[chainID] == nil {
+		_haltStore[chainID] = make(map[ethcommon.Address]map[TVaultHaltSource]*TVaultHalt)
+	}
+	if _haltStore[chainID][vault] == nil {
+		_haltStore[chainID][vault] = make(map[TVaultHaltSource]*TVaultHalt)
+	}
+	if halt == nil {
+		delete(_haltStore[chainID][vault], source)
+	} else {
+		_haltStore[chainID][vault][source] = halt
+	}
+
+	effective := effectiveHaltLocked(chainID, vault)
+
+	if v, ok := _vaultMap[chainID][vault]; ok && v.Details != nil {
+		if _metaDisabledBaseline[chainID] == nil {
+			_metaDisabledBaseline[chainID] = make(map[ethcommon.Address]metaDisabledBaseline)
+		}
+		baseline, ok := _metaDisabledBaseline[chainID][vault]
+		if !ok {
+			baseline = metaDisabledBaseline{deposits: v.Details.DepositsDisabled, withdrawals: v.Details.WithdrawalsDisabled}
+			_metaDisabledBaseline[chainID][vault] = baseline
+		}
+
+		depositsHalted, withdrawsHalted := false, false
+		for _, active := range _haltStore[chainID][vault] {
+			depositsHalted = depositsHalted || active.DepositsHalted
+			withdrawsHalted = withdrawsHalted || active.WithdrawsHalted
+		}
+
+		v.Details.Halt = effective
+		v.Details.DepositsDisabled = baseline.deposits || depositsHalted
+		v.Details.WithdrawalsDisabled = baseline.withdrawals || withdrawsHalted
+	}
+
+	// Publish the specific source transition that was just applied, not just the recomputed
+	// effective halt, so non-halting sources (eg. HaltSourceRoleChange) are still observable on
+	// the SSE stream even though they never become the representative TVaultHalt.
+	publishHaltTransition(chainID, vault, source, halt)
+}
+
+// effectiveHaltLocked returns the representative TVaultHalt for a vault across every source
+// currently active, in haltSourcePriority order, or nil if none are active. Callers must hold
+// _haltMu.
+func effectiveHaltLocked(chainID uint64, vault ethcommon.Address) *TVaultHalt {
+	active := _haltStore[chainID][vault]
+	for _, source := range haltSourcePriority {
+		if halt, ok := active[source]; ok {
+			return halt
+		}
+	}
+	return nil
+}
+
+/**********************************************************************************************
+** GetVaultHalt will, for a given chainID and vault, return the current chain-observed
+** TVaultHalt for that vault, and a boolean indicating whether one is currently in effect.
+**********************************************************************************************/
+func GetVaultHalt(chainID uint64, vault ethcommon.Address) (*TVaultHalt, bool) {
+	_haltMu.RLock()
+	defer _haltMu.RUnlock()
+	halt := effectiveHaltLocked(chainID, vault)
+	return halt, halt != nil
+}
+
+/**********************************************************************************************
+** GetVaultHaltSource will, for a given chainID, vault and source, return the last TVaultHalt
+** recorded for that specific source, and a boolean indicating whether one is currently active.
+** Unlike GetVaultHalt, this also surfaces sources that don't gate DepositsDisabled/
+** WithdrawalsDisabled on their own (eg. HaltSourceRoleChange), so their on-chain provenance
+** stays queryable even when they never become the vault's representative halt.
+**********************************************************************************************/
+func GetVaultHaltSource(chainID uint64, vault ethcommon.Address, source TVaultHaltSource) (*TVaultHalt, bool) {
+	_haltMu.RLock()
+	defer _haltMu.RUnlock()
+	halt, ok := _haltStore[chainID][vault][source]
+	return halt, ok
+}
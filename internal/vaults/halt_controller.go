@@ -0,0 +1,116 @@
+package vaults
+
+import (
+	"bufio"
+	"encoding/json"
+	"strconv"
+	"sync"
+
+	"github.com/gofiber/fiber/v2"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/yearn/ydaemon/common/types/common"
+)
+
+// THaltTransition is broadcast to every matching subscriber of StreamVaultHalts whenever a
+// single halt source for a vault changes, including the transition back to cleared
+// (Halt == nil).
+type THaltTransition struct {
+	ChainID uint64            `json:"chainID"`
+	Vault   ethcommon.Address `json:"vault"`
+	Source  TVaultHaltSource  `json:"source"`
+	Halt    *TVaultHalt       `json:"halt"`
+}
+
+// haltSubscriptionFilter scopes a StreamVaultHalts subscriber to a single chain and,
+// optionally, a single vault on that chain.
+type haltSubscriptionFilter struct {
+	chainID uint64
+	vault   *ethcommon.Address
+}
+
+func (f haltSubscriptionFilter) matches(chainID uint64, vault ethcommon.Address) bool {
+	if f.chainID != chainID {
+		return false
+	}
+	return f.vault == nil || *f.vault == vault
+}
+
+/**********************************************************************************************
+** Set of plumbing to fan a THaltTransition out to every currently connected SSE subscriber whose
+** haltSubscriptionFilter matches the transition's (chainID, vault). The _haltSubscribers
+** variable is not exported and is only used internally by the functions below.
+**********************************************************************************************/
+var _haltSubscribersMu sync.Mutex
+var _haltSubscribers = make(map[chan THaltTransition]haltSubscriptionFilter)
+
+func subscribeHaltTransitions(filter haltSubscriptionFilter) chan THaltTransition {
+	ch := make(chan THaltTransition, 16)
+	_haltSubscribersMu.Lock()
+	_haltSubscribers[ch] = filter
+	_haltSubscribersMu.Unlock()
+	return ch
+}
+
+func unsubscribeHaltTransitions(ch chan THaltTransition) {
+	_haltSubscribersMu.Lock()
+	delete(_haltSubscribers, ch)
+	_haltSubscribersMu.Unlock()
+	close(ch)
+}
+
+func publishHaltTransition(chainID uint64, vault ethcommon.Address, source TVaultHaltSource, halt *TVaultHalt) {
+	transition := THaltTransition{ChainID: chainID, Vault: vault, Source: source, Halt: halt}
+	_haltSubscribersMu.Lock()
+	defer _haltSubscribersMu.Unlock()
+	for ch, filter := range _haltSubscribers {
+		if !filter.matches(chainID, vault) {
+			continue
+		}
+		select {
+		case ch <- transition:
+		default:
+			// Slow subscriber: drop the transition rather than block the watcher goroutine.
+		}
+	}
+}
+
+/**********************************************************************************************
+** StreamVaultHalts is the HTTP handler answering `GET /{chainID}/vaults/halts/stream`. It opens
+** a Server-Sent Events stream and emits a THaltTransition for every halt-source change on
+** chainID, optionally narrowed to a single vault via the `vault` query parameter, until the
+** client disconnects.
+**********************************************************************************************/
+func StreamVaultHalts(c *fiber.Ctx) error {
+	chainID, err := strconv.ParseUint(c.Params(`chainID`), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(`invalid chainID`)
+	}
+
+	filter := haltSubscriptionFilter{chainID: chainID}
+	if vault := c.Query(`vault`); vault != `` {
+		vaultAddress := common.FromString(vault).ToAddress()
+		filter.vault = &vaultAddress
+	}
+
+	c.Set(fiber.HeaderContentType, `text/event-stream`)
+	c.Set(fiber.HeaderCacheControl, `no-cache`)
+	c.Set(fiber.HeaderConnection, `keep-alive`)
+
+	ch := subscribeHaltTransitions(filter)
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer unsubscribeHaltTransitions(ch)
+		for transition := range ch {
+			payload, err := json.Marshal(transition)
+			if err != nil {
+				continue
+			}
+			if _, err := w.Write([]byte("data: " + string(payload) + "\n\n")); err != nil {
+				return
+			}
+			if err := w.Flush(); err != nil {
+				return
+			}
+		}
+	})
+	return nil
+}
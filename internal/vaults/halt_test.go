@@ -0,0 +1,72 @@
+package vaults
+
+import (
+	"testing"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+)
+
+// TestHaltSourcesAreIndependent verifies that an emergency-shutdown halt survives a subsequent,
+// unrelated guardian/management role change, and that clearing the role-change source (which
+// never halted anything to begin with) does not affect it either.
+func TestHaltSourcesAreIndependent(t *testing.T) {
+	chainID := uint64(1)
+	vault := ethcommon.HexToAddress(`0x7777777777777777777777777777777777777777`)
+	_vaultMap[chainID] = map[ethcommon.Address]*TVault{vault: {Details: &TVaultDetails{}}}
+
+	setVaultHalt(chainID, vault, HaltSourceEmergencyShutdown, &TVaultHalt{
+		Source:         HaltSourceEmergencyShutdown,
+		DepositsHalted: true,
+	})
+	halt, ok := GetVaultHalt(chainID, vault)
+	if !ok || halt.Source != HaltSourceEmergencyShutdown {
+		t.Fatalf("expected an active emergency-shutdown halt, got %+v (ok=%v)", halt, ok)
+	}
+
+	// A role-change source must never be synthesized as a halting event by the watcher, but even
+	// if one were recorded with both Halted flags false, it must not displace a higher-priority,
+	// still-active halt.
+	setVaultHalt(chainID, vault, HaltSourceRoleChange, &TVaultHalt{Source: HaltSourceRoleChange})
+	halt, ok = GetVaultHalt(chainID, vault)
+	if !ok || halt.Source != HaltSourceEmergencyShutdown {
+		t.Fatalf("role-change source must not clobber an active emergency-shutdown halt, got %+v (ok=%v)", halt, ok)
+	}
+
+	setVaultHalt(chainID, vault, HaltSourceRoleChange, nil)
+	halt, ok = GetVaultHalt(chainID, vault)
+	if !ok || halt.Source != HaltSourceEmergencyShutdown {
+		t.Fatalf("clearing role-change source must not affect the emergency-shutdown halt, got %+v (ok=%v)", halt, ok)
+	}
+
+	setVaultHalt(chainID, vault, HaltSourceEmergencyShutdown, nil)
+	if halt, ok := GetVaultHalt(chainID, vault); ok {
+		t.Fatalf("expected no active halt after clearing emergency-shutdown, got %+v", halt)
+	}
+}
+
+// TestDepositLimitHaltClears verifies that a deposit-limit-zero halt clears once the deposit
+// limit is raised again, mirroring the emergency-shutdown branch's clear-on-false behavior.
+func TestDepositLimitHaltClears(t *testing.T) {
+	chainID := uint64(2)
+	vault := ethcommon.HexToAddress(`0x8888888888888888888888888888888888888888`)
+	_vaultMap[chainID] = map[ethcommon.Address]*TVault{vault: {Details: &TVaultDetails{}}}
+
+	setVaultHalt(chainID, vault, HaltSourceDepositLimitZero, &TVaultHalt{
+		Source:         HaltSourceDepositLimitZero,
+		DepositsHalted: true,
+	})
+	if halt, ok := GetVaultHalt(chainID, vault); !ok || halt.Source != HaltSourceDepositLimitZero {
+		t.Fatalf("expected an active deposit-limit-zero halt, got %+v (ok=%v)", halt, ok)
+	}
+	if !_vaultMap[chainID][vault].Details.DepositsDisabled {
+		t.Fatalf("expected DepositsDisabled to be true while the deposit limit is zero")
+	}
+
+	setVaultHalt(chainID, vault, HaltSourceDepositLimitZero, nil)
+	if halt, ok := GetVaultHalt(chainID, vault); ok {
+		t.Fatalf("expected no active halt once the deposit limit is restored, got %+v", halt)
+	}
+	if _vaultMap[chainID][vault].Details.DepositsDisabled {
+		t.Fatalf("expected DepositsDisabled to clear once the deposit limit is restored")
+	}
+}
@@ -0,0 +1,152 @@
+package vaults
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/yearn/ydaemon/common/logs"
+)
+
+// Topic0 hashes for the on-chain signals HaltWatcher reacts to. EmergencyShutdown and
+// UpdateDepositLimit are standard Yearn vault events; the guardian/management role change
+// signature is the vault's UpdateGuardian/UpdateManagement pair, watched together and recorded
+// for provenance only: neither halts deposits/withdrawals (see recordRoleChange).
+var emergencyShutdownSignature = ethcommon.HexToHash(`0xba40372a3a724dca3c57156128ef1e896724b65b37a17f190b1ad5de68f3a4f3`)
+var updateDepositLimitSignature = ethcommon.HexToHash(`0xae565aab888bca5e19e25a13db7b0c9144305bf55cb0f3f4d724f730e5acdd62`)
+var updateGuardianSignature = ethcommon.HexToHash(`0x837b9ad138a0a1839a9637afce5306a5c13e23eb63365686843a5319a243609c`)
+var updateManagementSignature = ethcommon.HexToHash(`0xff54978127edd34aec0f9061fb3b155fbe0ededdfa881ee3e0d541d3a1eef438`)
+
+// THaltLogFetcher is the subset of ethclient.Client used by HaltWatcher.
+type THaltLogFetcher interface {
+	SubscribeFilterLogs(ctx context.Context, query ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error)
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+}
+
+// HaltWatcher tails a vault's contract (and optionally its strategies) for the on-chain events
+// that should flip a TVaultHalt on or off, and pushes the resulting transitions into
+// setVaultHalt, which in turn fans them out to SSE subscribers via publishHaltTransition.
+type HaltWatcher struct {
+	chainID    uint64
+	client     THaltLogFetcher
+	vault      ethcommon.Address
+	strategies []ethcommon.Address
+}
+
+// NewHaltWatcher returns a HaltWatcher for vault (and its strategies) on chainID.
+func NewHaltWatcher(chainID uint64, client THaltLogFetcher, vault ethcommon.Address, strategies []ethcommon.Address) *HaltWatcher {
+	return &HaltWatcher{chainID: chainID, client: client, vault: vault, strategies: strategies}
+}
+
+/**********************************************************************************************
+** Watch subscribes to EmergencyShutdown, guardian/management role changes and
+** setDepositLimit(0) on the vault (health-check reverts are detected out of band, by the
+** regular strategy harvest simulation noticing a revert, and reported via ReportHealthCheckRevert
+** instead). It blocks until ctx is cancelled.
+**********************************************************************************************/
+func (w *HaltWatcher) Watch(ctx context.Context) error {
+	query := ethereum.FilterQuery{
+		Addresses: append([]ethcommon.Address{w.vault}, w.strategies...),
+		Topics:    [][]ethcommon.Hash{{emergencyShutdownSignature, updateDepositLimitSignature, updateGuardianSignature, updateManagementSignature}},
+	}
+	logChan := make(chan types.Log)
+	subscription, err := w.client.SubscribeFilterLogs(ctx, query, logChan)
+	if err != nil {
+		logs.Error(err)
+		return err
+	}
+	defer subscription.Unsubscribe()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-subscription.Err():
+			logs.Error(err)
+			return err
+		case logEntry := <-logChan:
+			w.handleLog(ctx, logEntry)
+		}
+	}
+}
+
+func (w *HaltWatcher) handleLog(ctx context.Context, logEntry types.Log) {
+	header, err := w.client.HeaderByNumber(ctx, new(big.Int).SetUint64(logEntry.BlockNumber))
+	if err != nil {
+		logs.Error(err)
+		return
+	}
+
+	switch logEntry.Topics[0] {
+	case emergencyShutdownSignature:
+		shutdown := len(logEntry.Data) > 0 && logEntry.Data[len(logEntry.Data)-1] != 0
+		if shutdown {
+			setVaultHalt(w.chainID, w.vault, HaltSourceEmergencyShutdown, &TVaultHalt{
+				Reason:          `vault entered emergency shutdown`,
+				Source:          HaltSourceEmergencyShutdown,
+				EffectiveBlock:  logEntry.BlockNumber,
+				EffectiveTime:   header.Time,
+				DepositsHalted:  true,
+				WithdrawsHalted: false,
+			})
+		} else {
+			setVaultHalt(w.chainID, w.vault, HaltSourceEmergencyShutdown, nil)
+		}
+	case updateDepositLimitSignature:
+		limitIsZero := true
+		for _, b := range logEntry.Data {
+			if b != 0 {
+				limitIsZero = false
+				break
+			}
+		}
+		if limitIsZero {
+			setVaultHalt(w.chainID, w.vault, HaltSourceDepositLimitZero, &TVaultHalt{
+				Reason:         `deposit limit set to zero`,
+				Source:         HaltSourceDepositLimitZero,
+				EffectiveBlock: logEntry.BlockNumber,
+				EffectiveTime:  header.Time,
+				DepositsHalted: true,
+			})
+		} else {
+			// Deposit limit was raised again: clear any halt this source previously recorded.
+			setVaultHalt(w.chainID, w.vault, HaltSourceDepositLimitZero, nil)
+		}
+	case updateGuardianSignature:
+		w.recordRoleChange(`guardian`, logEntry.BlockNumber, header.Time)
+	case updateManagementSignature:
+		w.recordRoleChange(`management`, logEntry.BlockNumber, header.Time)
+	}
+}
+
+// recordRoleChange tracks a guardian/management rotation under HaltSourceRoleChange for
+// provenance (queryable via GetVaultHaltSource and observable on the SSE stream). Both
+// DepositsHalted/WithdrawsHalted are left false: a routine admin action is not a halt in itself
+// and must never flip DepositsDisabled/WithdrawalsDisabled or clobber a halt another source is
+// currently reporting.
+func (w *HaltWatcher) recordRoleChange(role string, blockNumber uint64, blockTime uint64) {
+	setVaultHalt(w.chainID, w.vault, HaltSourceRoleChange, &TVaultHalt{
+		Reason:         `vault ` + role + ` role changed`,
+		Source:         HaltSourceRoleChange,
+		EffectiveBlock: blockNumber,
+		EffectiveTime:  blockTime,
+	})
+}
+
+/**********************************************************************************************
+** ReportHealthCheckRevert lets the strategy harvest simulation (which already calls into each
+** strategy's health check before a real harvest) flag a vault as halted when that health check
+** reverts, without the watcher having to understand strategy-specific health check ABIs.
+**********************************************************************************************/
+func ReportHealthCheckRevert(chainID uint64, vault ethcommon.Address, blockNumber uint64, blockTime uint64, reason string) {
+	setVaultHalt(chainID, vault, HaltSourceHealthCheck, &TVaultHalt{
+		Reason:          reason,
+		Source:          HaltSourceHealthCheck,
+		EffectiveBlock:  blockNumber,
+		EffectiveTime:   blockTime,
+		DepositsHalted:  true,
+		WithdrawsHalted: true,
+	})
+}
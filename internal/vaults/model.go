@@ -2,6 +2,7 @@ package vaults
 
 import (
 	"strings"
+	"time"
 
 	ethcommon "github.com/ethereum/go-ethereum/common"
 	"github.com/yearn/ydaemon/common/bigNumber"
@@ -84,6 +85,7 @@ type TVaultDetails struct {
 	AllowZapIn            bool           `json:"allowZapIn"`
 	AllowZapOut           bool           `json:"allowZapOut"`
 	Retired               bool           `json:"retired"`
+	Halt                  *TVaultHalt    `json:"halt,omitempty"`
 }
 
 // TVault is the main structure returned by the API when trying to get all the vaults for a specific network
@@ -110,6 +112,7 @@ type TVault struct {
 	Strategies         []strategies.TStrategy `json:"strategies"`
 	Migration          TMigration             `json:"migration"`
 	Details            *TVaultDetails         `json:"details"`
+	Allocation         *TVaultAllocation      `json:"allocation,omitempty"`
 }
 
 func (t *TVault) BuildNames(metaVaultName string) {
@@ -183,7 +186,7 @@ func (t *TVault) BuildMigration(chainID uint64) {
 	t.Migration = migration
 }
 
-func (t *TVault) BuildAPY(chainID uint64) {
+func (t *TVault) BuildAPY(chainID uint64, blockNumber uint64) {
 	apy := TAPY{}
 	aggregatedVault, ok := store.Store.AggregatedVault[chainID][common.FromAddress(t.Address)]
 
@@ -215,6 +218,17 @@ func (t *TVault) BuildAPY(chainID uint64) {
 		}
 	}
 	t.APY = apy
+	recordAPYPoint(chainID, t.Address, blockNumber, uint64(time.Now().Unix()), apy.NetAPY)
+}
+
+/**********************************************************************************************
+** BuildTVL assigns tvl to the vault and records a time-series point for it, so
+** GetVaultTVLSeries has history to serve. It should be called every time the vault's TVL is
+** recomputed, the same way BuildAPY is called every time the APY is recomputed.
+**********************************************************************************************/
+func (t *TVault) BuildTVL(chainID uint64, blockNumber uint64, tvl TTVL) {
+	t.TVL = tvl
+	recordTVLPoint(chainID, t.Address, blockNumber, uint64(time.Now().Unix()), tvl.TVL)
 }
 
 /**********************************************************************************************
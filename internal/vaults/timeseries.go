@@ -0,0 +1,125 @@
+package vaults
+
+import (
+	ethcommon "github.com/ethereum/go-ethereum/common"
+)
+
+// TSeriesMetric identifies which vault metric a TSeriesPoint belongs to.
+type TSeriesMetric string
+
+const (
+	SeriesMetricTVL TSeriesMetric = `tvl`
+	SeriesMetricAPY TSeriesMetric = `apy`
+)
+
+// TSeriesPoint is a single recorded sample of TTVL or TAPY for a vault, taken every time that
+// value is recomputed.
+type TSeriesPoint struct {
+	BlockNumber uint64  `json:"blockNumber"`
+	Timestamp   uint64  `json:"timestamp"`
+	Value       float64 `json:"value"`
+}
+
+// TSeriesBucket is a single OHLC-style downsampled bucket returned by GetVaultTVLSeries and
+// GetVaultAPYSeries, covering [From, From+Step).
+type TSeriesBucket struct {
+	From  uint64  `json:"from"`
+	To    uint64  `json:"to"`
+	Open  float64 `json:"open"`
+	High  float64 `json:"high"`
+	Low   float64 `json:"low"`
+	Close float64 `json:"close"`
+}
+
+// TSeriesStore is the pluggable backend behind the time-series subsystem. RecordPoint is called
+// every time TVault.TVL or TVault.APY is recomputed; QueryRange answers a bounded range query
+// used to build the OHLC buckets returned by the API. Implementations: the default
+// inMemorySeriesStore (a ring buffer), and optional Postgres/Timescale or Parquet-on-disk
+// backends for deployments that need retention beyond what fits in memory.
+type TSeriesStore interface {
+	RecordPoint(chainID uint64, vault ethcommon.Address, metric TSeriesMetric, point TSeriesPoint)
+	QueryRange(chainID uint64, vault ethcommon.Address, metric TSeriesMetric, from uint64, to uint64) []TSeriesPoint
+}
+
+// _seriesStore is the active TSeriesStore for the process. It defaults to an in-memory ring
+// buffer and can be swapped via SetSeriesStore during application bootstrap.
+var _seriesStore TSeriesStore = newInMemorySeriesStore(defaultSeriesCapacity)
+
+// SetSeriesStore overrides the backend used by the time-series subsystem. It must be called
+// before any vault is built, since it is not safe to swap stores while points are being
+// recorded.
+func SetSeriesStore(store TSeriesStore) {
+	_seriesStore = store
+}
+
+/**********************************************************************************************
+** recordTVLPoint and recordAPYPoint are called from the vault builder pipeline every time a
+** vault's TTVL/TAPY is recomputed, so the configured TSeriesStore always has a full history to
+** serve range queries from.
+**********************************************************************************************/
+func recordTVLPoint(chainID uint64, vault ethcommon.Address, blockNumber uint64, timestamp uint64, tvl float64) {
+	_seriesStore.RecordPoint(chainID, vault, SeriesMetricTVL, TSeriesPoint{BlockNumber: blockNumber, Timestamp: timestamp, Value: tvl})
+}
+
+func recordAPYPoint(chainID uint64, vault ethcommon.Address, blockNumber uint64, timestamp uint64, apy float64) {
+	_seriesStore.RecordPoint(chainID, vault, SeriesMetricAPY, TSeriesPoint{BlockNumber: blockNumber, Timestamp: timestamp, Value: apy})
+}
+
+/**********************************************************************************************
+** GetVaultTVLSeries will, for a given chainID and vault, return the TVL history between from and
+** to, downsampled into OHLC-style buckets of width step (in seconds).
+**********************************************************************************************/
+func GetVaultTVLSeries(chainID uint64, vault ethcommon.Address, from uint64, to uint64, step uint64) []TSeriesBucket {
+	points := _seriesStore.QueryRange(chainID, vault, SeriesMetricTVL, from, to)
+	return bucketSeries(points, from, to, step)
+}
+
+/**********************************************************************************************
+** GetVaultAPYSeries will, for a given chainID and vault, return the APY history between from
+** and to, downsampled into OHLC-style buckets of width step (in seconds).
+**********************************************************************************************/
+func GetVaultAPYSeries(chainID uint64, vault ethcommon.Address, from uint64, to uint64, step uint64) []TSeriesBucket {
+	points := _seriesStore.QueryRange(chainID, vault, SeriesMetricAPY, from, to)
+	return bucketSeries(points, from, to, step)
+}
+
+// bucketSeries groups points into fixed-width [from, from+step) windows and reduces each window
+// to an OHLC bucket. Points are assumed to already be sorted by Timestamp, which every
+// TSeriesStore implementation guarantees.
+func bucketSeries(points []TSeriesPoint, from uint64, to uint64, step uint64) []TSeriesBucket {
+	if step == 0 || from >= to {
+		return nil
+	}
+
+	var buckets []TSeriesBucket
+	var current *TSeriesBucket
+	bucketEnd := from + step
+
+	for _, point := range points {
+		if point.Timestamp < from || point.Timestamp >= to {
+			continue
+		}
+		for point.Timestamp >= bucketEnd {
+			if current != nil {
+				buckets = append(buckets, *current)
+				current = nil
+			}
+			bucketEnd += step
+		}
+		if current == nil {
+			current = &TSeriesBucket{From: bucketEnd - step, To: bucketEnd, Open: point.Value, High: point.Value, Low: point.Value, Close: point.Value}
+			continue
+		}
+		if point.Value > current.High {
+			current.High = point.Value
+		}
+		if point.Value < current.Low {
+			current.Low = point.Value
+		}
+		current.Close = point.Value
+	}
+	if current != nil {
+		buckets = append(buckets, *current)
+	}
+	return buckets
+}
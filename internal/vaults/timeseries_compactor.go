@@ -0,0 +1,75 @@
+package vaults
+
+import (
+	"context"
+	"time"
+)
+
+// TCompactorConfig controls how long SeriesCompactor keeps raw points before rolling them up.
+type TCompactorConfig struct {
+	// RawRetention is how long points are kept at full resolution before being rolled up.
+	RawRetention time.Duration
+	// RollupStep is the bucket width (in seconds) used for points older than RawRetention.
+	RollupStep uint64
+	// Interval is how often the compactor sweeps the store.
+	Interval time.Duration
+}
+
+// DefaultCompactorConfig keeps 7 days of raw points and rolls anything older up to hourly
+// buckets, sweeping once an hour.
+var DefaultCompactorConfig = TCompactorConfig{
+	RawRetention: 7 * 24 * time.Hour,
+	RollupStep:   3600,
+	Interval:     time.Hour,
+}
+
+// SeriesCompactor periodically replaces raw points older than config.RawRetention with their
+// hourly/daily rollups, keeping a TSeriesStore's footprint bounded regardless of backend.
+type SeriesCompactor struct {
+	store  TCompactingSeriesStore
+	config TCompactorConfig
+	now    func() time.Time
+}
+
+// TCompactingSeriesStore is implemented by a TSeriesStore that also supports replacing a range
+// of raw points with a rolled-up summary. The default inMemorySeriesStore and the Postgres and
+// Parquet stores all implement it; for a store that does not, NewSeriesCompactor returns nil.
+type TCompactingSeriesStore interface {
+	TSeriesStore
+	Rollup(chainID uint64, metric TSeriesMetric, olderThan uint64, step uint64)
+}
+
+// NewSeriesCompactor returns a SeriesCompactor for store using config, or nil if store does not
+// support compaction.
+func NewSeriesCompactor(store TSeriesStore, config TCompactorConfig, now func() time.Time) *SeriesCompactor {
+	compactingStore, ok := store.(TCompactingSeriesStore)
+	if !ok {
+		return nil
+	}
+	if now == nil {
+		now = time.Now
+	}
+	return &SeriesCompactor{store: compactingStore, config: config, now: now}
+}
+
+/**********************************************************************************************
+** Run sweeps the store on config.Interval, rolling up points older than config.RawRetention for
+** both the TVL and APY metrics of every chain in chainIDs. It blocks until ctx is cancelled.
+**********************************************************************************************/
+func (c *SeriesCompactor) Run(ctx context.Context, chainIDs []uint64) {
+	ticker := time.NewTicker(c.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			cutoff := uint64(c.now().Add(-c.config.RawRetention).Unix())
+			for _, chainID := range chainIDs {
+				c.store.Rollup(chainID, SeriesMetricTVL, cutoff, c.config.RollupStep)
+				c.store.Rollup(chainID, SeriesMetricAPY, cutoff, c.config.RollupStep)
+			}
+		}
+	}
+}
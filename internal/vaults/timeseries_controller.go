@@ -0,0 +1,52 @@
+package vaults
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/yearn/ydaemon/common/types/common"
+)
+
+func parseSeriesRangeParams(c *fiber.Ctx) (chainID uint64, vault common.Address, from uint64, to uint64, step uint64, err error) {
+	chainID, err = strconv.ParseUint(c.Params(`chainID`), 10, 64)
+	if err != nil {
+		return
+	}
+	vault = common.FromString(c.Params(`address`))
+	from, err = strconv.ParseUint(c.Query(`from`, `0`), 10, 64)
+	if err != nil {
+		return
+	}
+	to, err = strconv.ParseUint(c.Query(`to`), 10, 64)
+	if err != nil {
+		return
+	}
+	step, err = strconv.ParseUint(c.Query(`step`, `3600`), 10, 64)
+	return
+}
+
+/**********************************************************************************************
+** GetVaultTVLSeriesHandler is the HTTP handler answering `GET /{chainID}/vaults/{address}/tvl/series`.
+** It returns the vault's TVL history between the `from`/`to` query params, downsampled into
+** OHLC buckets of width `step` seconds.
+**********************************************************************************************/
+func GetVaultTVLSeriesHandler(c *fiber.Ctx) error {
+	chainID, vault, from, to, step, err := parseSeriesRangeParams(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(err.Error())
+	}
+	return c.Status(fiber.StatusOK).JSON(GetVaultTVLSeries(chainID, vault.ToAddress(), from, to, step))
+}
+
+/**********************************************************************************************
+** GetVaultAPYSeriesHandler is the HTTP handler answering `GET /{chainID}/vaults/{address}/apy/series`.
+** It returns the vault's APY history between the `from`/`to` query params, downsampled into
+** OHLC buckets of width `step` seconds.
+**********************************************************************************************/
+func GetVaultAPYSeriesHandler(c *fiber.Ctx) error {
+	chainID, vault, from, to, step, err := parseSeriesRangeParams(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(err.Error())
+	}
+	return c.Status(fiber.StatusOK).JSON(GetVaultAPYSeries(chainID, vault.ToAddress(), from, to, step))
+}
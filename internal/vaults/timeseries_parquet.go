@@ -0,0 +1,172 @@
+package vaults
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/xitongsys/parquet-go-source/local"
+	"github.com/xitongsys/parquet-go/reader"
+	"github.com/xitongsys/parquet-go/writer"
+	"github.com/yearn/ydaemon/common/logs"
+)
+
+// parquetSeriesPoint mirrors TSeriesPoint with the struct tags parquet-go needs to infer a
+// schema; it is kept private since it is purely a serialization concern.
+type parquetSeriesPoint struct {
+	BlockNumber uint64  `parquet:"name=block_number, type=INT64"`
+	Timestamp   uint64  `parquet:"name=timestamp, type=INT64"`
+	Value       float64 `parquet:"name=value, type=DOUBLE"`
+}
+
+// parquetSeriesStore is an optional TSeriesStore backend that persists each (chainID, vault,
+// metric) series to its own Parquet file under dir, for deployments that want cheap, queryable
+// cold storage instead of a running Postgres/Timescale instance. Since parquet-go has no native
+// append mode, every write rewrites the whole file: RecordPoint reads the existing rows, adds
+// the new one, and writes the full set back out, rather than truncating history on every call.
+type parquetSeriesStore struct {
+	mu  sync.Mutex
+	dir string
+}
+
+// NewParquetSeriesStore returns a TSeriesStore that persists each series to its own Parquet
+// file under dir.
+func NewParquetSeriesStore(dir string) TSeriesStore {
+	return &parquetSeriesStore{dir: dir}
+}
+
+func (s *parquetSeriesStore) path(chainID uint64, vault ethcommon.Address, metric TSeriesMetric) string {
+	return filepath.Join(s.dir, fmt.Sprintf(`%d_%s_%s.parquet`, chainID, vault.Hex(), metric))
+}
+
+// readRows returns every row currently persisted for (chainID, vault, metric), or nil if the
+// file does not exist yet.
+func (s *parquetSeriesStore) readRows(chainID uint64, vault ethcommon.Address, metric TSeriesMetric) []parquetSeriesPoint {
+	path := s.path(chainID, vault, metric)
+	if _, err := os.Stat(path); err != nil {
+		return nil
+	}
+
+	fr, err := local.NewLocalFileReader(path)
+	if err != nil {
+		logs.Error(err)
+		return nil
+	}
+	defer fr.Close()
+
+	pr, err := reader.NewParquetReader(fr, new(parquetSeriesPoint), 1)
+	if err != nil {
+		logs.Error(err)
+		return nil
+	}
+	defer pr.ReadStop()
+
+	rows := make([]parquetSeriesPoint, pr.GetNumRows())
+	if err := pr.Read(&rows); err != nil {
+		logs.Error(err)
+		return nil
+	}
+	return rows
+}
+
+// writeRows overwrites (chainID, vault, metric)'s file with exactly rows.
+func (s *parquetSeriesStore) writeRows(chainID uint64, vault ethcommon.Address, metric TSeriesMetric, rows []parquetSeriesPoint) {
+	fw, err := local.NewLocalFileWriter(s.path(chainID, vault, metric))
+	if err != nil {
+		logs.Error(err)
+		return
+	}
+	defer fw.Close()
+
+	pw, err := writer.NewParquetWriter(fw, new(parquetSeriesPoint), 1)
+	if err != nil {
+		logs.Error(err)
+		return
+	}
+	defer pw.WriteStop()
+
+	for _, row := range rows {
+		if err := pw.Write(row); err != nil {
+			logs.Error(err)
+			return
+		}
+	}
+}
+
+func (s *parquetSeriesStore) RecordPoint(chainID uint64, vault ethcommon.Address, metric TSeriesMetric, point TSeriesPoint) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rows := s.readRows(chainID, vault, metric)
+	rows = append(rows, parquetSeriesPoint{BlockNumber: point.BlockNumber, Timestamp: point.Timestamp, Value: point.Value})
+	s.writeRows(chainID, vault, metric, rows)
+}
+
+func (s *parquetSeriesStore) QueryRange(chainID uint64, vault ethcommon.Address, metric TSeriesMetric, from uint64, to uint64) []TSeriesPoint {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var points []TSeriesPoint
+	for _, row := range s.readRows(chainID, vault, metric) {
+		if row.Timestamp >= from && row.Timestamp < to {
+			points = append(points, TSeriesPoint{BlockNumber: row.BlockNumber, Timestamp: row.Timestamp, Value: row.Value})
+		}
+	}
+	sort.Slice(points, func(i, j int) bool { return points[i].Timestamp < points[j].Timestamp })
+	return points
+}
+
+/**********************************************************************************************
+** Rollup replaces every raw point older than olderThan in every vault's file for this dir with
+** one averaged point per step-wide bucket, reusing the same bucketing logic as
+** inMemorySeriesStore.Rollup. There is no index of what files exist, so the vaults are found by
+** globbing dir for the files of chainID and metric and recovering each address from its name.
+**********************************************************************************************/
+func (s *parquetSeriesStore) Rollup(chainID uint64, metric TSeriesMetric, olderThan uint64, step uint64) {
+	if step == 0 {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	matches, err := filepath.Glob(filepath.Join(s.dir, fmt.Sprintf(`%d_*_%s.parquet`, chainID, metric)))
+	if err != nil {
+		logs.Error(err)
+		return
+	}
+
+	for _, path := range matches {
+		vault, ok := vaultFromSeriesPath(path, chainID, metric)
+		if !ok {
+			continue
+		}
+		rows := s.readRows(chainID, vault, metric)
+		points := make([]TSeriesPoint, len(rows))
+		for i, row := range rows {
+			points[i] = TSeriesPoint{BlockNumber: row.BlockNumber, Timestamp: row.Timestamp, Value: row.Value}
+		}
+		rolledUp := rollupPoints(points, olderThan, step)
+
+		newRows := make([]parquetSeriesPoint, len(rolledUp))
+		for i, point := range rolledUp {
+			newRows[i] = parquetSeriesPoint{BlockNumber: point.BlockNumber, Timestamp: point.Timestamp, Value: point.Value}
+		}
+		s.writeRows(chainID, vault, metric, newRows)
+	}
+}
+
+// vaultFromSeriesPath recovers the vault address encoded in a "<chainID>_<vault>_<metric>.parquet"
+// path produced by (*parquetSeriesStore).path.
+func vaultFromSeriesPath(path string, chainID uint64, metric TSeriesMetric) (ethcommon.Address, bool) {
+	base := filepath.Base(path)
+	prefix := fmt.Sprintf(`%d_`, chainID)
+	suffix := fmt.Sprintf(`_%s.parquet`, metric)
+	if len(base) <= len(prefix)+len(suffix) {
+		return ethcommon.Address{}, false
+	}
+	hex := base[len(prefix) : len(base)-len(suffix)]
+	return ethcommon.HexToAddress(hex), true
+}
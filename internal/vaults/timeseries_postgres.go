@@ -0,0 +1,117 @@
+package vaults
+
+import (
+	"database/sql"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/yearn/ydaemon/common/logs"
+)
+
+// postgresSeriesStore is an optional TSeriesStore backend for deployments that need retention
+// beyond what the default in-memory ring buffer can hold. It expects a `vault_series` table
+// with columns (chain_id, vault, metric, block_number, timestamp, value), and works equally
+// well against plain Postgres or a Timescale hypertable built on that table.
+type postgresSeriesStore struct {
+	db *sql.DB
+}
+
+// NewPostgresSeriesStore returns a TSeriesStore backed by db. Callers are expected to have
+// already run the `vault_series` migration and to pass it to SetSeriesStore during bootstrap.
+func NewPostgresSeriesStore(db *sql.DB) TSeriesStore {
+	return &postgresSeriesStore{db: db}
+}
+
+func (s *postgresSeriesStore) RecordPoint(chainID uint64, vault ethcommon.Address, metric TSeriesMetric, point TSeriesPoint) {
+	_, err := s.db.Exec(
+		`INSERT INTO vault_series (chain_id, vault, metric, block_number, timestamp, value) VALUES ($1, $2, $3, $4, $5, $6)`,
+		chainID, vault.Hex(), string(metric), point.BlockNumber, point.Timestamp, point.Value,
+	)
+	if err != nil {
+		logs.Error(err)
+	}
+}
+
+func (s *postgresSeriesStore) QueryRange(chainID uint64, vault ethcommon.Address, metric TSeriesMetric, from uint64, to uint64) []TSeriesPoint {
+	rows, err := s.db.Query(
+		`SELECT block_number, timestamp, value FROM vault_series
+		 WHERE chain_id = $1 AND vault = $2 AND metric = $3 AND timestamp >= $4 AND timestamp < $5
+		 ORDER BY timestamp ASC`,
+		chainID, vault.Hex(), string(metric), from, to,
+	)
+	if err != nil {
+		logs.Error(err)
+		return nil
+	}
+	defer rows.Close()
+
+	var points []TSeriesPoint
+	for rows.Next() {
+		var point TSeriesPoint
+		if err := rows.Scan(&point.BlockNumber, &point.Timestamp, &point.Value); err != nil {
+			logs.Error(err)
+			continue
+		}
+		points = append(points, point)
+	}
+	return points
+}
+
+/**********************************************************************************************
+** Rollup replaces every raw row older than olderThan for (chainID, metric) across every vault
+** with one averaged row per step-wide bucket. It stages the aggregated rows in a temp table
+** before deleting the raw rows that fed them, so the delete can never race with (or eat) its own
+** output. Rows at or after olderThan are left untouched.
+**********************************************************************************************/
+func (s *postgresSeriesStore) Rollup(chainID uint64, metric TSeriesMetric, olderThan uint64, step uint64) {
+	if step == 0 {
+		return
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		logs.Error(err)
+		return
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(
+		`CREATE TEMP TABLE vault_series_rollup (
+			chain_id BIGINT, vault TEXT, metric TEXT, block_number BIGINT, timestamp BIGINT, value DOUBLE PRECISION
+		) ON COMMIT DROP`,
+	); err != nil {
+		logs.Error(err)
+		return
+	}
+
+	if _, err := tx.Exec(
+		`INSERT INTO vault_series_rollup (chain_id, vault, metric, block_number, timestamp, value)
+		 SELECT chain_id, vault, metric, max(block_number), (timestamp / $4) * $4, avg(value)
+		 FROM vault_series
+		 WHERE chain_id = $1 AND metric = $2 AND timestamp < $3
+		 GROUP BY chain_id, vault, metric, (timestamp / $4)`,
+		chainID, string(metric), olderThan, step,
+	); err != nil {
+		logs.Error(err)
+		return
+	}
+
+	if _, err := tx.Exec(
+		`DELETE FROM vault_series WHERE chain_id = $1 AND metric = $2 AND timestamp < $3`,
+		chainID, string(metric), olderThan,
+	); err != nil {
+		logs.Error(err)
+		return
+	}
+
+	if _, err := tx.Exec(
+		`INSERT INTO vault_series (chain_id, vault, metric, block_number, timestamp, value)
+		 SELECT chain_id, vault, metric, block_number, timestamp, value FROM vault_series_rollup`,
+	); err != nil {
+		logs.Error(err)
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		logs.Error(err)
+	}
+}
@@ -0,0 +1,123 @@
+package vaults
+
+import (
+	"sort"
+	"sync"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+)
+
+// defaultSeriesCapacity bounds how many raw points the default in-memory store keeps per
+// (chainID, vault, metric) before evicting the oldest. At one point per recompute, this covers
+// one year (24 * 365 points) of hourly recomputation without unbounded growth.
+const defaultSeriesCapacity = 8760
+
+type seriesKey struct {
+	chainID uint64
+	vault   ethcommon.Address
+	metric  TSeriesMetric
+}
+
+// inMemorySeriesStore is the default TSeriesStore: a fixed-capacity ring buffer per
+// (chainID, vault, metric). It has no retention policy beyond its capacity, so deployments that
+// need durable long-range history should configure a Postgres/Timescale or Parquet-on-disk
+// store via SetSeriesStore instead.
+type inMemorySeriesStore struct {
+	mu       sync.RWMutex
+	capacity int
+	points   map[seriesKey][]TSeriesPoint
+}
+
+func newInMemorySeriesStore(capacity int) *inMemorySeriesStore {
+	return &inMemorySeriesStore{capacity: capacity, points: make(map[seriesKey][]TSeriesPoint)}
+}
+
+func (s *inMemorySeriesStore) RecordPoint(chainID uint64, vault ethcommon.Address, metric TSeriesMetric, point TSeriesPoint) {
+	key := seriesKey{chainID: chainID, vault: vault, metric: metric}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	series := append(s.points[key], point)
+	if len(series) > s.capacity {
+		series = series[len(series)-s.capacity:]
+	}
+	s.points[key] = series
+}
+
+func (s *inMemorySeriesStore) QueryRange(chainID uint64, vault ethcommon.Address, metric TSeriesMetric, from uint64, to uint64) []TSeriesPoint {
+	key := seriesKey{chainID: chainID, vault: vault, metric: metric}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var result []TSeriesPoint
+	for _, point := range s.points[key] {
+		if point.Timestamp >= from && point.Timestamp < to {
+			result = append(result, point)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Timestamp < result[j].Timestamp })
+	return result
+}
+
+/**********************************************************************************************
+** Rollup replaces every raw point older than olderThan, for every vault of chainID/metric
+** currently tracked, with one averaged point per step-wide bucket, satisfying
+** TCompactingSeriesStore so SeriesCompactor can keep this store's footprint bounded. Points at
+** or after olderThan are left untouched.
+**********************************************************************************************/
+func (s *inMemorySeriesStore) Rollup(chainID uint64, metric TSeriesMetric, olderThan uint64, step uint64) {
+	if step == 0 {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key, series := range s.points {
+		if key.chainID != chainID || key.metric != metric {
+			continue
+		}
+		s.points[key] = rollupPoints(series, olderThan, step)
+	}
+}
+
+// rollupPoints splits series into points older than olderThan (downsampled into one averaged
+// point per step-wide bucket) and points at or after olderThan (kept raw), then returns them
+// concatenated in chronological order.
+func rollupPoints(series []TSeriesPoint, olderThan uint64, step uint64) []TSeriesPoint {
+	sort.Slice(series, func(i, j int) bool { return series[i].Timestamp < series[j].Timestamp })
+
+	var raw []TSeriesPoint
+	buckets := make(map[uint64][]TSeriesPoint)
+	var bucketOrder []uint64
+	for _, point := range series {
+		if point.Timestamp >= olderThan {
+			raw = append(raw, point)
+			continue
+		}
+		bucketStart := (point.Timestamp / step) * step
+		if _, ok := buckets[bucketStart]; !ok {
+			bucketOrder = append(bucketOrder, bucketStart)
+		}
+		buckets[bucketStart] = append(buckets[bucketStart], point)
+	}
+
+	rolledUp := make([]TSeriesPoint, 0, len(bucketOrder))
+	for _, bucketStart := range bucketOrder {
+		bucket := buckets[bucketStart]
+		var sum float64
+		var lastBlock uint64
+		for _, point := range bucket {
+			sum += point.Value
+			if point.BlockNumber > lastBlock {
+				lastBlock = point.BlockNumber
+			}
+		}
+		rolledUp = append(rolledUp, TSeriesPoint{
+			BlockNumber: lastBlock,
+			Timestamp:   bucketStart,
+			Value:       sum / float64(len(bucket)),
+		})
+	}
+
+	return append(rolledUp, raw...)
+}
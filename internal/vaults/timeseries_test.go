@@ -0,0 +1,59 @@
+package vaults
+
+import (
+	"testing"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+)
+
+// TestBucketSeries verifies that bucketSeries groups points into fixed-width windows and reduces
+// each window to the right OHLC values, including a window with no points in between two
+// populated ones (which must not produce a bogus bucket).
+func TestBucketSeries(t *testing.T) {
+	points := []TSeriesPoint{
+		{Timestamp: 0, Value: 10},
+		{Timestamp: 5, Value: 20},
+		{Timestamp: 9, Value: 5},
+		{Timestamp: 25, Value: 100},
+	}
+
+	buckets := bucketSeries(points, 0, 30, 10)
+	if len(buckets) != 2 {
+		t.Fatalf("expected 2 non-empty buckets, got %d: %+v", len(buckets), buckets)
+	}
+
+	first := buckets[0]
+	if first.Open != 10 || first.Close != 5 || first.High != 20 || first.Low != 5 {
+		t.Errorf("first bucket OHLC wrong: %+v", first)
+	}
+
+	second := buckets[1]
+	if second.From != 20 || second.Open != 100 || second.Close != 100 {
+		t.Errorf("second bucket wrong: %+v", second)
+	}
+}
+
+// TestInMemorySeriesStoreRollup verifies that Rollup downsamples points older than olderThan
+// into one averaged point per bucket while leaving newer points untouched.
+func TestInMemorySeriesStoreRollup(t *testing.T) {
+	store := newInMemorySeriesStore(1000)
+	chainID := uint64(1)
+	vault := ethcommon.HexToAddress(`0x9999999999999999999999999999999999999999`)
+
+	store.RecordPoint(chainID, vault, SeriesMetricTVL, TSeriesPoint{Timestamp: 0, Value: 10})
+	store.RecordPoint(chainID, vault, SeriesMetricTVL, TSeriesPoint{Timestamp: 1800, Value: 20})
+	store.RecordPoint(chainID, vault, SeriesMetricTVL, TSeriesPoint{Timestamp: 3700, Value: 1000})
+
+	store.Rollup(chainID, SeriesMetricTVL, 3600, 3600)
+
+	points := store.QueryRange(chainID, vault, SeriesMetricTVL, 0, 10000)
+	if len(points) != 2 {
+		t.Fatalf("expected 1 rolled-up bucket + 1 untouched raw point, got %d: %+v", len(points), points)
+	}
+	if points[0].Timestamp != 0 || points[0].Value != 15 {
+		t.Errorf("expected rolled-up bucket {0, 15}, got %+v", points[0])
+	}
+	if points[1].Timestamp != 3700 || points[1].Value != 1000 {
+		t.Errorf("expected untouched raw point {3700, 1000}, got %+v", points[1])
+	}
+}